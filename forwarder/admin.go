@@ -0,0 +1,258 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/ThingsIXFoundation/packet-handling/gateway"
+	"github.com/brocaar/lorawan"
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminAuthConfig configures verification of the JWTs the admin API
+// requires, in the spirit of the coreos/go-oidc discovery flow: tokens are
+// verified against the JSON Web Key Set published by an OIDC provider.
+type AdminAuthConfig struct {
+	// Issuer is the expected `iss` claim of admin API tokens.
+	Issuer string
+	// JWKSURL is the JWKS endpoint used to verify token signatures.
+	JWKSURL string
+	// Audience is the expected `aud` claim (the admin API's OIDC client id).
+	Audience string
+}
+
+// AdminClaims are the token claims the admin API relies on. Owner scopes a
+// caller's gateway management: ListGateways, AddGateway and DeleteGateway
+// only ever read or mutate gateways whose Owner matches the token's owner
+// claim, so a single admin API deployment can share gateway management
+// across multiple tenants. Router management (ListRouters, ReloadRouters)
+// only requires an authenticated caller; see their doc comments for what
+// they do and don't affect.
+type AdminClaims struct {
+	Owner common.Address `json:"owner"`
+}
+
+type adminClaimsContextKey struct{}
+
+func claimsFromContext(ctx context.Context) (AdminClaims, bool) {
+	claims, ok := ctx.Value(adminClaimsContextKey{}).(AdminClaims)
+	return claims, ok
+}
+
+func contextWithClaims(ctx context.Context, claims AdminClaims) context.Context {
+	return context.WithValue(ctx, adminClaimsContextKey{}, claims)
+}
+
+// newAdminVerifier builds the OIDC ID token verifier admin API requests are
+// checked against.
+func newAdminVerifier(cfg AdminAuthConfig) (*oidc.IDTokenVerifier, error) {
+	if cfg.JWKSURL == "" {
+		return nil, fmt.Errorf("admin API: no JWKS URL configured")
+	}
+
+	keySet := oidc.NewRemoteKeySet(context.Background(), cfg.JWKSURL)
+	return oidc.NewVerifier(cfg.Issuer, keySet, &oidc.Config{ClientID: cfg.Audience}), nil
+}
+
+func verifyAdminToken(ctx context.Context, verifier *oidc.IDTokenVerifier, rawToken string) (AdminClaims, error) {
+	idToken, err := verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return AdminClaims{}, fmt.Errorf("invalid token: %w", err)
+	}
+
+	var claims AdminClaims
+	if err := idToken.Claims(&claims); err != nil {
+		return AdminClaims{}, fmt.Errorf("invalid token claims: %w", err)
+	}
+	if claims.Owner == (common.Address{}) {
+		return AdminClaims{}, fmt.Errorf("invalid token claims: no owner claim")
+	}
+
+	return claims, nil
+}
+
+// newAdminHTTPAuthMiddleware authenticates incoming HTTP requests by
+// verifying the bearer token in the Authorization header and injecting the
+// resulting AdminClaims into the request context.
+func newAdminHTTPAuthMiddleware(cfg AdminAuthConfig) (func(http.Handler) http.Handler, error) {
+	verifier, err := newAdminVerifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rawToken := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if rawToken == "" {
+				http.Error(w, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+
+			claims, err := verifyAdminToken(r.Context(), verifier, rawToken)
+			if err != nil {
+				logrus.WithError(err).Warn("admin API: rejected request")
+				http.Error(w, "invalid token", http.StatusUnauthorized)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(contextWithClaims(r.Context(), claims)))
+		})
+	}, nil
+}
+
+// errForbidden is returned when the caller's owner claim doesn't permit the
+// requested gateway mutation.
+var errForbidden = fmt.Errorf("not permitted to manage this gateway")
+
+// errGatewayExists is returned by AddGateway when localID is already present
+// in the store.
+var errGatewayExists = fmt.Errorf("gateway already exists")
+
+// adminService implements the gateway and router management operations
+// exposed by the admin API. It is shared by the HTTP and gRPC transports so
+// the authorization rules only need to be written once.
+type adminService struct {
+	// store is the gateway store the running forwarder was configured
+	// with.
+	store gateway.Store
+	// listRouters returns the last router snapshot observed from the chain;
+	// see (*adminService).ListRouters.
+	listRouters func() ([]AdminRouterInfo, error)
+	// reloadRouters refreshes that snapshot from the chain; see
+	// (*adminService).ReloadRouters.
+	reloadRouters func() error
+}
+
+// ListGateways returns the gateways owned by the caller.
+func (s *adminService) ListGateways(ctx context.Context) ([]AdminGatewayInfo, error) {
+	claims, ok := claimsFromContext(ctx)
+	if !ok {
+		return nil, errForbidden
+	}
+
+	var owned []AdminGatewayInfo
+	for _, gw := range s.store.Gateways() {
+		if gw.Owner == claims.Owner {
+			owned = append(owned, adminGatewayInfo(gw))
+		}
+	}
+	return owned, nil
+}
+
+// AddGateway generates a new gateway key pair for localID, adds it to the
+// store owned by the caller and returns the new gateway, whose compressed
+// public key the transports hand back so the operator can onboard it in the
+// gateway registry.
+func (s *adminService) AddGateway(ctx context.Context, localID lorawan.EUI64) (*gateway.Gateway, error) {
+	claims, ok := claimsFromContext(ctx)
+	if !ok {
+		return nil, errForbidden
+	}
+
+	for _, existing := range s.store.Gateways() {
+		if existing.LocalGatewayID == localID {
+			return nil, errGatewayExists
+		}
+	}
+
+	gw, err := gateway.GenerateNewGateway(localID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to generate gateway key: %w", err)
+	}
+	gw.Owner = claims.Owner
+
+	if err := s.store.Add(ctx, gw); err != nil {
+		return nil, fmt.Errorf("unable to add gateway: %w", err)
+	}
+
+	logrus.WithFields(logrus.Fields{"local-id": localID, "owner": claims.Owner}).Info("added gateway through admin API")
+	return gw, nil
+}
+
+// DeleteGateway removes localID from the store, as long as it is owned by
+// the caller.
+func (s *adminService) DeleteGateway(ctx context.Context, localID lorawan.EUI64) error {
+	claims, ok := claimsFromContext(ctx)
+	if !ok {
+		return errForbidden
+	}
+
+	for _, gw := range s.store.Gateways() {
+		if gw.LocalGatewayID == localID {
+			if gw.Owner != claims.Owner {
+				return errForbidden
+			}
+			return s.store.Delete(ctx, localID)
+		}
+	}
+	return nil
+}
+
+// ListRouters returns the last router snapshot read from the chain, from the
+// shared registry cache. It does not dial the chain itself and it does not
+// reach into a running forwarder process; use ReloadRouters to force a fresh
+// on-chain read.
+func (s *adminService) ListRouters(ctx context.Context) ([]AdminRouterInfo, error) {
+	if _, ok := claimsFromContext(ctx); !ok {
+		return nil, errForbidden
+	}
+	return s.listRouters()
+}
+
+// ReloadRouters forces an immediate on-chain read of the router registry and
+// refreshes the shared registry cache with it. The admin API is a separate
+// process from a running forwarder: this does not push the new snapshot
+// into a live forwarder's in-memory routing table, only into the cache file
+// that forwarder falls back to once its own RPC call fails. When the chain
+// read fails and the cache already holds a snapshot, it returns no error and
+// the cache is left as it was.
+func (s *adminService) ReloadRouters(ctx context.Context) error {
+	if _, ok := claimsFromContext(ctx); !ok {
+		return errForbidden
+	}
+	return s.reloadRouters()
+}
+
+// AdminGatewayInfo is the gateway information exposed by the admin API.
+type AdminGatewayInfo struct {
+	LocalGatewayID      lorawan.EUI64  `json:"local_gateway_id"`
+	NetworkGatewayID    lorawan.EUI64  `json:"network_gateway_id"`
+	Owner               common.Address `json:"owner"`
+	CompressedPublicKey []byte         `json:"compressed_public_key"`
+}
+
+func adminGatewayInfo(gw *gateway.Gateway) AdminGatewayInfo {
+	return AdminGatewayInfo{
+		LocalGatewayID:      gw.LocalGatewayID,
+		NetworkGatewayID:    gw.NetworkGatewayID,
+		Owner:               gw.Owner,
+		CompressedPublicKey: gw.CompressedPubKeyBytes(),
+	}
+}
+
+// AdminRouterInfo is the router information exposed by the admin API.
+type AdminRouterInfo struct {
+	ID       [32]byte       `json:"id"`
+	Endpoint string         `json:"endpoint"`
+	Owner    common.Address `json:"owner"`
+}
@@ -0,0 +1,187 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/brocaar/lorawan"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// The admin gRPC service is described by admin.proto. Its messages are
+// plain Go structs exchanged as JSON rather than protobuf: the service is
+// internal to a single forwarder deployment, so we trade the protoc build
+// step for a service description we can hand-maintain next to admin.proto.
+
+func init() {
+	encoding.RegisterCodec(adminJSONCodec{})
+}
+
+type adminJSONCodec struct{}
+
+func (adminJSONCodec) Marshal(v interface{}) ([]byte, error)      { return json.Marshal(v) }
+func (adminJSONCodec) Unmarshal(data []byte, v interface{}) error { return json.Unmarshal(data, v) }
+func (adminJSONCodec) Name() string                               { return "json" }
+
+// adminGRPCServer adapts adminService to the grpc.ServiceDesc below.
+type adminGRPCServer struct {
+	svc *adminService
+}
+
+// newAdminAuthInterceptor authenticates incoming gRPC requests the same way
+// newAdminHTTPAuthMiddleware authenticates HTTP requests: it verifies the
+// bearer token in the "authorization" metadata entry and injects the
+// resulting AdminClaims into the request context.
+func newAdminAuthInterceptor(cfg AdminAuthConfig) (grpc.UnaryServerInterceptor, error) {
+	verifier, err := newAdminVerifier(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok || len(md.Get("authorization")) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+
+		rawToken := strings.TrimPrefix(md.Get("authorization")[0], "Bearer ")
+		claims, err := verifyAdminToken(ctx, verifier, rawToken)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return handler(contextWithClaims(ctx, claims), req)
+	}, nil
+}
+
+// adminListGatewaysResponse, adminAddGatewayRequest etc. are the
+// request/response messages defined by admin.proto. AddGateway responds with
+// AdminGatewayInfo, the counterpart of its Gateway message.
+
+type adminListGatewaysResponse struct {
+	Gateways []AdminGatewayInfo `json:"gateways"`
+}
+
+type adminAddGatewayRequest struct {
+	LocalGatewayID lorawan.EUI64 `json:"local_gateway_id"`
+}
+
+type adminDeleteGatewayRequest struct {
+	LocalGatewayID lorawan.EUI64 `json:"local_gateway_id"`
+}
+
+type adminListRoutersResponse struct {
+	Routers []AdminRouterInfo `json:"routers"`
+}
+
+func (s *adminGRPCServer) listGateways(ctx context.Context, _ *struct{}) (*adminListGatewaysResponse, error) {
+	gateways, err := s.svc.ListGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &adminListGatewaysResponse{Gateways: gateways}, nil
+}
+
+func (s *adminGRPCServer) addGateway(ctx context.Context, req *adminAddGatewayRequest) (*AdminGatewayInfo, error) {
+	gw, err := s.svc.AddGateway(ctx, req.LocalGatewayID)
+	if err != nil {
+		return nil, err
+	}
+	info := adminGatewayInfo(gw)
+	return &info, nil
+}
+
+func (s *adminGRPCServer) deleteGateway(ctx context.Context, req *adminDeleteGatewayRequest) (*struct{}, error) {
+	if err := s.svc.DeleteGateway(ctx, req.LocalGatewayID); err != nil {
+		return nil, err
+	}
+	return &struct{}{}, nil
+}
+
+func (s *adminGRPCServer) listRouters(ctx context.Context, _ *struct{}) (*adminListRoutersResponse, error) {
+	routers, err := s.svc.ListRouters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &adminListRoutersResponse{Routers: routers}, nil
+}
+
+func (s *adminGRPCServer) reloadRouters(ctx context.Context, _ *struct{}) (*struct{}, error) {
+	if err := s.svc.ReloadRouters(ctx); err != nil {
+		return nil, err
+	}
+	return &struct{}{}, nil
+}
+
+// unaryAdminHandler adapts one adminGRPCServer method to a grpc.MethodDesc
+// handler, decoding the request with dec and running through interceptor so
+// authentication still applies.
+func unaryAdminHandler[Req any, Resp any](fullMethod string, call func(s *adminGRPCServer, ctx context.Context, req *Req) (*Resp, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		req := new(Req)
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+
+		s := srv.(*adminGRPCServer)
+		if interceptor == nil {
+			return call(s, ctx, req)
+		}
+
+		info := &grpc.UnaryServerInfo{Server: s, FullMethod: fullMethod}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(s, ctx, req.(*Req))
+		}
+		return interceptor(ctx, req, info, handler)
+	}
+}
+
+// adminServiceDesc mirrors the `Admin` service defined in admin.proto.
+var adminServiceDesc = grpc.ServiceDesc{
+	ServiceName: "thingsix.forwarder.admin.v1.Admin",
+	HandlerType: (*adminGRPCServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListGateways",
+			Handler:    unaryAdminHandler[struct{}, adminListGatewaysResponse]("/thingsix.forwarder.admin.v1.Admin/ListGateways", (*adminGRPCServer).listGateways),
+		},
+		{
+			MethodName: "AddGateway",
+			Handler:    unaryAdminHandler[adminAddGatewayRequest, AdminGatewayInfo]("/thingsix.forwarder.admin.v1.Admin/AddGateway", (*adminGRPCServer).addGateway),
+		},
+		{
+			MethodName: "DeleteGateway",
+			Handler:    unaryAdminHandler[adminDeleteGatewayRequest, struct{}]("/thingsix.forwarder.admin.v1.Admin/DeleteGateway", (*adminGRPCServer).deleteGateway),
+		},
+		{
+			MethodName: "ListRouters",
+			Handler:    unaryAdminHandler[struct{}, adminListRoutersResponse]("/thingsix.forwarder.admin.v1.Admin/ListRouters", (*adminGRPCServer).listRouters),
+		},
+		{
+			MethodName: "ReloadRouters",
+			Handler:    unaryAdminHandler[struct{}, struct{}]("/thingsix.forwarder.admin.v1.Admin/ReloadRouters", (*adminGRPCServer).reloadRouters),
+		},
+	},
+	Metadata: "admin.proto",
+}
@@ -0,0 +1,145 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/brocaar/lorawan"
+)
+
+// newAdminHTTPHandler builds the routes the admin HTTP API exposes. Callers
+// are expected to wrap the returned handler with an authentication
+// middleware such as newAdminHTTPAuthMiddleware.
+func newAdminHTTPHandler(svc *adminService) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/v1/gateways", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			handleListGateways(svc, w, r)
+		case http.MethodPost:
+			handleAddGateway(svc, w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/v1/gateways/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handleDeleteGateway(svc, w, r)
+	})
+
+	mux.HandleFunc("/v1/routers", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handleListRouters(svc, w, r)
+	})
+
+	mux.HandleFunc("/v1/routers/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		handleReloadRouters(svc, w, r)
+	})
+
+	return mux
+}
+
+func handleListGateways(svc *adminService, w http.ResponseWriter, r *http.Request) {
+	gateways, err := svc.ListGateways(r.Context())
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, gateways)
+}
+
+func handleAddGateway(svc *adminService, w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		LocalGatewayID lorawan.EUI64 `json:"local_gateway_id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	gw, err := svc.AddGateway(r.Context(), req.LocalGatewayID)
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusCreated, adminGatewayInfo(gw))
+}
+
+func handleDeleteGateway(svc *adminService, w http.ResponseWriter, r *http.Request) {
+	var localID lorawan.EUI64
+	if err := localID.UnmarshalText([]byte(strings.TrimPrefix(r.URL.Path, "/v1/gateways/"))); err != nil {
+		http.Error(w, "invalid gateway id", http.StatusBadRequest)
+		return
+	}
+
+	if err := svc.DeleteGateway(r.Context(), localID); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func handleListRouters(svc *adminService, w http.ResponseWriter, r *http.Request) {
+	routers, err := svc.ListRouters(r.Context())
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, routers)
+}
+
+func handleReloadRouters(svc *adminService, w http.ResponseWriter, r *http.Request) {
+	if err := svc.ReloadRouters(r.Context()); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	switch err {
+	case errForbidden:
+		http.Error(w, err.Error(), http.StatusForbidden)
+	case errGatewayExists:
+		http.Error(w, err.Error(), http.StatusConflict)
+	case errRoutersUnavailable:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
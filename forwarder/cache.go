@@ -0,0 +1,228 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	cacheGatewaysBucket    = []byte("registry-gateways")
+	cacheRoutersBucket     = []byte("registry-routers")
+	cacheRouterSnapshotKey = []byte("snapshot")
+)
+
+// cachedGatewayRegistration is the last successfully observed trusted
+// registration of a gateway in the gateway registry.
+type cachedGatewayRegistration struct {
+	Owner      common.Address
+	ObservedAt time.Time
+}
+
+// cachedRouter is the subset of router_registry.Router that is cheap to
+// persist and sufficient to reconstruct a Router once the accounter for the
+// current process is known.
+type cachedRouter struct {
+	ID       [32]byte
+	Endpoint string
+	Networks []lorawan.NetID
+	Owner    common.Address
+}
+
+// cachedRouterSnapshot is the last successfully observed routing table.
+type cachedRouterSnapshot struct {
+	ConfirmedBlock uint64
+	Routers        []cachedRouter
+	ObservedAt     time.Time
+}
+
+// RegistryCache persists the last successfully observed on-chain gateway and
+// router registry lookups so the forwarder keeps trusting gateways and
+// routing to known routers when the RPC node is unreachable or rate
+// limiting, instead of silently losing them.
+type RegistryCache struct {
+	db  *bolt.DB
+	ttl time.Duration
+}
+
+// loadRegistryCache opens the registry cache configured in cfg. It returns a
+// nil cache without error when no cache path is configured, which callers
+// must treat as "caching disabled".
+func loadRegistryCache(cfg *Config) (*RegistryCache, error) {
+	if cfg.Forwarder.RegistryCache.Path == "" {
+		return nil, nil
+	}
+
+	ttl := 24 * time.Hour
+	if cfg.Forwarder.RegistryCache.TTL != nil {
+		ttl = *cfg.Forwarder.RegistryCache.TTL
+	}
+
+	return openRegistryCache(cfg.Forwarder.RegistryCache.Path, ttl)
+}
+
+func openRegistryCache(path string, ttl time.Duration) (*RegistryCache, error) {
+	return openRegistryCacheOpts(path, ttl, bolt.Options{Timeout: 5 * time.Second})
+}
+
+// openRegistryCacheReadOnly opens the registry cache at path without taking
+// the writer lock a running forwarder holds on it for its entire lifetime.
+// bbolt still can't share a single file between a writer and a reader
+// indefinitely, so this fails fast instead of blocking for the usual 5s
+// write-open timeout: `forwarder cache inspect` is meant to be pointed at a
+// cache file that isn't also open elsewhere, e.g. after stopping the
+// forwarder or against a copy of its cache file.
+func openRegistryCacheReadOnly(path string) (*RegistryCache, error) {
+	return openRegistryCacheOpts(path, 0, bolt.Options{ReadOnly: true, Timeout: 500 * time.Millisecond})
+}
+
+func openRegistryCacheOpts(path string, ttl time.Duration, opts bolt.Options) (*RegistryCache, error) {
+	db, err := bolt.Open(path, 0600, &opts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open registry cache %s: %w", path, err)
+	}
+
+	if !opts.ReadOnly {
+		err = db.Update(func(tx *bolt.Tx) error {
+			if _, err := tx.CreateBucketIfNotExists(cacheGatewaysBucket); err != nil {
+				return err
+			}
+			_, err := tx.CreateBucketIfNotExists(cacheRoutersBucket)
+			return err
+		})
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("unable to initialize registry cache %s: %w", path, err)
+		}
+	}
+
+	return &RegistryCache{db: db, ttl: ttl}, nil
+}
+
+// Close releases the resources held by the cache. It is safe to call on a
+// nil *RegistryCache so callers don't need to special-case a disabled cache.
+func (c *RegistryCache) Close() error {
+	if c == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// PutGateway write-through caches the given trusted gateway registration.
+func (c *RegistryCache) PutGateway(id [32]byte, entry cachedGatewayRegistration) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(entry); err != nil {
+		return fmt.Errorf("unable to encode cached gateway registration: %w", err)
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cacheGatewaysBucket).Put(id[:], buf.Bytes())
+	})
+}
+
+// GetGateway returns the last cached registration for id, if any, and
+// whether it is older than the cache TTL.
+func (c *RegistryCache) GetGateway(id [32]byte) (entry cachedGatewayRegistration, stale bool, found bool) {
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(cacheGatewaysBucket)
+		if bucket == nil {
+			return nil
+		}
+		raw := bucket.Get(id[:])
+		if raw == nil {
+			return nil
+		}
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&entry); err != nil {
+			logrus.WithError(err).Warn("unable to decode cached gateway registration")
+			return nil
+		}
+		found = true
+		return nil
+	})
+
+	stale = found && time.Since(entry.ObservedAt) > c.ttl
+	return entry, stale, found
+}
+
+// DumpGateways returns every gateway registration currently in the cache,
+// keyed by registry id, without touching the chain.
+func (c *RegistryCache) DumpGateways() (map[[32]byte]cachedGatewayRegistration, error) {
+	gateways := make(map[[32]byte]cachedGatewayRegistration)
+
+	err := c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(cacheGatewaysBucket)
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			var entry cachedGatewayRegistration
+			if err := gob.NewDecoder(bytes.NewReader(v)).Decode(&entry); err != nil {
+				return nil // skip corrupt entry
+			}
+			var id [32]byte
+			copy(id[:], k)
+			gateways[id] = entry
+			return nil
+		})
+	})
+
+	return gateways, err
+}
+
+// PutRouterSnapshot write-through caches the given routing table.
+func (c *RegistryCache) PutRouterSnapshot(snapshot cachedRouterSnapshot) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		return fmt.Errorf("unable to encode cached router snapshot: %w", err)
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(cacheRoutersBucket).Put(cacheRouterSnapshotKey, buf.Bytes())
+	})
+}
+
+// GetRouterSnapshot returns the last cached routing table, if any, and
+// whether it is older than the cache TTL.
+func (c *RegistryCache) GetRouterSnapshot() (snapshot cachedRouterSnapshot, stale bool, found bool) {
+	_ = c.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(cacheRoutersBucket)
+		if bucket == nil {
+			return nil
+		}
+		raw := bucket.Get(cacheRouterSnapshotKey)
+		if raw == nil {
+			return nil
+		}
+		if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&snapshot); err != nil {
+			logrus.WithError(err).Warn("unable to decode cached router snapshot")
+			return nil
+		}
+		found = true
+		return nil
+	})
+
+	stale = found && time.Since(snapshot.ObservedAt) > c.ttl
+	return snapshot, stale, found
+}
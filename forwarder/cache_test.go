@@ -0,0 +1,70 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRefreshRouterSnapshotFallsBackToCacheOnRPCFailure(t *testing.T) {
+	cache, err := openRegistryCache(filepath.Join(t.TempDir(), "cache.db"), 24*time.Hour)
+	if err != nil {
+		t.Fatalf("openRegistryCache: %v", err)
+	}
+	defer cache.Close()
+
+	want := cachedRouterSnapshot{
+		ConfirmedBlock: 42,
+		Routers:        []cachedRouter{{ID: [32]byte{1}, Endpoint: "router.example:1234", Owner: common.HexToAddress("0xa1")}},
+		ObservedAt:     time.Now(),
+	}
+	if err := cache.PutRouterSnapshot(want); err != nil {
+		t.Fatalf("PutRouterSnapshot: %v", err)
+	}
+
+	// /nonexistent/rpc.sock has no scheme, so it is dialed as an IPC socket
+	// and fails fast with "no such file" instead of the longer HTTP dial
+	// timeout - no real RPC node is required for this test.
+	cfg := &Config{
+		BlockChain: BlockChainConfig{Polygon: PolygonConfig{Endpoint: "/nonexistent/rpc.sock"}},
+	}
+
+	routers, confirmedBlock, err := refreshRouterSnapshot(cfg, cache)
+	if err != nil {
+		t.Fatalf("refreshRouterSnapshot: %v", err)
+	}
+	if confirmedBlock != want.ConfirmedBlock {
+		t.Errorf("confirmedBlock = %d, want %d", confirmedBlock, want.ConfirmedBlock)
+	}
+	if len(routers) != 1 || routers[0].ID != want.Routers[0].ID {
+		t.Errorf("routers = %+v, want %+v", routers, want.Routers)
+	}
+}
+
+func TestRefreshRouterSnapshotErrorsWithoutCache(t *testing.T) {
+	cfg := &Config{
+		BlockChain: BlockChainConfig{Polygon: PolygonConfig{Endpoint: "/nonexistent/rpc.sock"}},
+	}
+
+	if _, _, err := refreshRouterSnapshot(cfg, nil); err == nil {
+		t.Fatal("refreshRouterSnapshot with no cache and an unreachable chain returned nil error")
+	}
+}
@@ -0,0 +1,244 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// AdminCmd runs the control-plane admin API: an HTTP+gRPC service, guarded
+// by OIDC issued JWTs, for managing the gateway store and the router
+// registry cache of a forwarder deployment. It runs as its own process and
+// does not change a running forwarder's in-memory state. It is meant to be
+// wired into the forwarder's root cobra command.
+var AdminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "run the control-plane admin API",
+	RunE:  runAdmin,
+}
+
+func init() {
+	AdminCmd.Flags().String("listen-http", ":8443", "address the HTTP admin API listens on")
+	AdminCmd.Flags().String("listen-grpc", ":8444", "address the gRPC admin API listens on")
+	AdminCmd.Flags().String("tls-cert", "", "TLS certificate file used by both listeners")
+	AdminCmd.Flags().String("tls-key", "", "TLS private key file used by both listeners")
+	AdminCmd.Flags().String("oidc-issuer", "", "issuer that signs admin API tokens")
+	AdminCmd.Flags().String("oidc-jwks-url", "", "JWKS URL used to verify admin API tokens")
+	AdminCmd.Flags().String("oidc-audience", "", "expected audience (client id) of admin API tokens")
+
+	AdminCmd.Flags().String("store-yaml", "", "manage the gateways in the yaml file store at the given path")
+	AdminCmd.Flags().String("store-bbolt", "", "manage the gateways in the bbolt store at the given path")
+	AdminCmd.Flags().StringSlice("store-etcd", nil, "manage the gateways in the etcd store with the given endpoints")
+
+	AdminCmd.Flags().String("rpc-endpoint", "", "blockchain RPC endpoint router management reads the router registry from")
+	AdminCmd.Flags().Uint64("rpc-chain-id", 0, "chain id the RPC endpoint is expected to serve")
+	AdminCmd.Flags().Uint64("rpc-confirmations", 0, "number of confirmations a block needs before it is read from")
+	AdminCmd.Flags().String("router-registry", "", "router registry contract address")
+	AdminCmd.Flags().String("registry-cache", "", "path to the registry cache router management falls back to when the chain is unreachable, shared with the forwarder's own cache")
+	AdminCmd.Flags().Duration("registry-cache-ttl", 0, "how long a cached router snapshot is considered fresh before being reported as stale (defaults to 24h)")
+}
+
+// errRoutersUnavailable is returned by ListRouters/ReloadRouters when the
+// admin API was started without --rpc-endpoint/--router-registry, so it has
+// no router registry to read or cache to fall back to.
+var errRoutersUnavailable = fmt.Errorf("router management is not available: admin API was started without router registry access")
+
+func runAdmin(cmd *cobra.Command, args []string) error {
+	httpAddr, _ := cmd.Flags().GetString("listen-http")
+	grpcAddr, _ := cmd.Flags().GetString("listen-grpc")
+	tlsCertFile, _ := cmd.Flags().GetString("tls-cert")
+	tlsKeyFile, _ := cmd.Flags().GetString("tls-key")
+
+	authCfg := AdminAuthConfig{}
+	authCfg.Issuer, _ = cmd.Flags().GetString("oidc-issuer")
+	authCfg.JWKSURL, _ = cmd.Flags().GetString("oidc-jwks-url")
+	authCfg.Audience, _ = cmd.Flags().GetString("oidc-audience")
+
+	if tlsCertFile == "" || tlsKeyFile == "" {
+		return fmt.Errorf("--tls-cert and --tls-key are required")
+	}
+
+	cert, err := tls.LoadX509KeyPair(tlsCertFile, tlsKeyFile)
+	if err != nil {
+		return fmt.Errorf("unable to load admin API TLS certificate: %w", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	httpAuth, err := newAdminHTTPAuthMiddleware(authCfg)
+	if err != nil {
+		return fmt.Errorf("unable to set up admin API authentication: %w", err)
+	}
+
+	grpcAuth, err := newAdminAuthInterceptor(authCfg)
+	if err != nil {
+		return fmt.Errorf("unable to set up admin API authentication: %w", err)
+	}
+
+	store, err := gatewayStoreFromFlags(cmd, "store")
+	if err != nil {
+		return fmt.Errorf("gateway store: %w", err)
+	}
+	defer store.Close()
+
+	routerCfg, cache, err := adminRouterConfigFromFlags(cmd)
+	if err != nil {
+		return fmt.Errorf("router registry: %w", err)
+	}
+	defer cache.Close()
+
+	svc := &adminService{
+		store:         store,
+		listRouters:   func() ([]AdminRouterInfo, error) { return adminListRouters(routerCfg, cache) },
+		reloadRouters: func() error { return adminReloadRouters(routerCfg, cache) },
+	}
+
+	grpcListener, err := net.Listen("tcp", grpcAddr)
+	if err != nil {
+		return fmt.Errorf("unable to listen for gRPC admin API on %s: %w", grpcAddr, err)
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.Creds(credentials.NewTLS(tlsConfig)),
+		grpc.UnaryInterceptor(grpcAuth),
+		grpc.ForceServerCodec(adminJSONCodec{}),
+	)
+	grpcServer.RegisterService(&adminServiceDesc, &adminGRPCServer{svc: svc})
+
+	go func() {
+		logrus.WithField("addr", grpcAddr).Info("admin gRPC API listening")
+		if err := grpcServer.Serve(grpcListener); err != nil {
+			logrus.WithError(err).Error("admin gRPC API stopped")
+		}
+	}()
+
+	httpServer := &http.Server{
+		Addr:      httpAddr,
+		Handler:   httpAuth(newAdminHTTPHandler(svc)),
+		TLSConfig: tlsConfig,
+	}
+
+	logrus.WithField("addr", httpAddr).Info("admin HTTP API listening")
+	return httpServer.ListenAndServeTLS(tlsCertFile, tlsKeyFile)
+}
+
+// adminRouterConfigFromFlags builds the *Config router management reads the
+// router registry and its registry cache through, from the --rpc-*,
+// --router-registry and --registry-cache* flags. It returns a nil cfg when
+// --rpc-endpoint or --router-registry weren't set, in which case router
+// management reports errRoutersUnavailable.
+func adminRouterConfigFromFlags(cmd *cobra.Command) (*Config, *RegistryCache, error) {
+	rpcEndpoint, _ := cmd.Flags().GetString("rpc-endpoint")
+	routerRegistry, _ := cmd.Flags().GetString("router-registry")
+	if rpcEndpoint == "" || routerRegistry == "" {
+		return nil, nil, nil
+	}
+
+	chainID, _ := cmd.Flags().GetUint64("rpc-chain-id")
+	if chainID == 0 {
+		return nil, nil, fmt.Errorf("--rpc-chain-id is required when --rpc-endpoint is set")
+	}
+	if !common.IsHexAddress(routerRegistry) {
+		return nil, nil, fmt.Errorf("--router-registry %q is not a valid address", routerRegistry)
+	}
+
+	confirmations, _ := cmd.Flags().GetUint64("rpc-confirmations")
+	cachePath, _ := cmd.Flags().GetString("registry-cache")
+	cacheTTL, _ := cmd.Flags().GetDuration("registry-cache-ttl")
+
+	cfg := &Config{
+		BlockChain: BlockChainConfig{Polygon: PolygonConfig{
+			Endpoint:      rpcEndpoint,
+			ChainID:       chainID,
+			Confirmations: confirmations,
+		}},
+		Forwarder: ForwarderConfig{
+			Routers: RoutersConfig{OnChain: OnChainRoutersConfig{
+				RegistryContract: common.HexToAddress(routerRegistry),
+			}},
+		},
+	}
+	if cachePath != "" {
+		cfg.Forwarder.RegistryCache.Path = cachePath
+		if cacheTTL > 0 {
+			cfg.Forwarder.RegistryCache.TTL = &cacheTTL
+		}
+	}
+
+	cache, err := loadRegistryCache(cfg)
+	if err != nil {
+		logrus.WithError(err).Warn("unable to open registry cache, continuing without it")
+	}
+
+	return cfg, cache, nil
+}
+
+// adminListRouters serves the last cached router snapshot. It deliberately
+// does not read the chain: the admin API is a separate process from the
+// running forwarder, so there is no "current routing table" to synchronously
+// read here, and a list endpoint shouldn't dial the RPC node on every call.
+// Use ReloadRouters to force an on-chain read.
+func adminListRouters(cfg *Config, cache *RegistryCache) ([]AdminRouterInfo, error) {
+	if cfg == nil {
+		return nil, errRoutersUnavailable
+	}
+
+	if cache == nil {
+		return nil, nil
+	}
+	snapshot, _, found := cache.GetRouterSnapshot()
+	if !found {
+		return nil, nil
+	}
+
+	infos := make([]AdminRouterInfo, len(snapshot.Routers))
+	for i, r := range snapshot.Routers {
+		infos[i] = AdminRouterInfo{ID: r.ID, Endpoint: r.Endpoint, Owner: r.Owner}
+	}
+	return infos, nil
+}
+
+// adminReloadRouters forces an immediate on-chain read of the router
+// registry and refreshes the shared registry cache file with it.
+//
+// This does NOT reach into a running forwarder process: the admin API and
+// the forwarder are separate processes, each opening the cache file
+// independently, and the forwarder only ever reads it as a fallback when its
+// own RPC call fails, not on a healthy refresh. So while the forwarder is up
+// and healthy, this call updates a cache the forwarder isn't consulting;
+// while the forwarder is down (or unhealthy), it refreshes the snapshot
+// routing resumes from. Cross-process invalidation of a live forwarder's
+// in-memory routing table is not implemented.
+//
+// When the chain read fails and the cache already holds a snapshot,
+// refreshRouterSnapshot falls back to that snapshot, so this returns no
+// error and leaves the cache as it was.
+func adminReloadRouters(cfg *Config, cache *RegistryCache) error {
+	if cfg == nil {
+		return errRoutersUnavailable
+	}
+	_, _, err := refreshRouterSnapshot(cfg, cache)
+	return err
+}
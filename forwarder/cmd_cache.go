@@ -0,0 +1,82 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// CacheCmd is the `cache` command group for inspecting the registry cache.
+// It is meant to be wired into the forwarder's root cobra command.
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "inspect the on-chain registry cache",
+}
+
+var cacheInspectCmd = &cobra.Command{
+	Use:   "inspect",
+	Short: "dump the gateways and routers currently trusted from cache, without contacting the chain",
+	Long: "dump the gateways and routers currently trusted from cache, without contacting the chain.\n" +
+		"The cache is opened read-only, but bbolt still can't share a database file with a process " +
+		"that holds it open for writing, so point --path at a cache file whose owning forwarder is " +
+		"stopped, or at a copy of it.",
+	RunE: runCacheInspect,
+}
+
+func init() {
+	cacheInspectCmd.Flags().String("path", "", "path to the registry cache database")
+	CacheCmd.AddCommand(cacheInspectCmd)
+}
+
+func runCacheInspect(cmd *cobra.Command, args []string) error {
+	path, _ := cmd.Flags().GetString("path")
+	if path == "" {
+		return fmt.Errorf("--path is required")
+	}
+
+	cache, err := openRegistryCacheReadOnly(path)
+	if err != nil {
+		return err
+	}
+	defer cache.Close()
+
+	gateways, err := cache.DumpGateways()
+	if err != nil {
+		return fmt.Errorf("unable to dump cached gateways: %w", err)
+	}
+
+	fmt.Printf("gateways (%d):\n", len(gateways))
+	for id, entry := range gateways {
+		fmt.Printf("  %x  owner=%s  observed-at=%s\n", id, entry.Owner, entry.ObservedAt.Format("2006-01-02T15:04:05Z07:00"))
+	}
+
+	snapshot, _, ok := cache.GetRouterSnapshot()
+	if !ok {
+		fmt.Println("routers: no cached snapshot")
+		return nil
+	}
+
+	fmt.Printf("routers (%d) as of confirmed block %d, observed at %s:\n",
+		len(snapshot.Routers), snapshot.ConfirmedBlock, snapshot.ObservedAt.Format("2006-01-02T15:04:05Z07:00"))
+	for _, r := range snapshot.Routers {
+		fmt.Printf("  %x  endpoint=%s  owner=%s\n", r.ID, r.Endpoint, r.Owner)
+	}
+
+	return nil
+}
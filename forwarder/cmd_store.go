@@ -0,0 +1,103 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ThingsIXFoundation/packet-handling/gateway"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// StoreCmd is the `store` command group for inspecting and migrating
+// gateway stores. It is meant to be wired into the forwarder's root cobra
+// command.
+var StoreCmd = &cobra.Command{
+	Use:   "store",
+	Short: "manage the gateway store",
+}
+
+var storeMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "copy all gateways from one store backend into another",
+	RunE:  runStoreMigrate,
+}
+
+func init() {
+	storeMigrateCmd.Flags().String("from-yaml", "", "migrate gateways from the yaml file store at the given path")
+	storeMigrateCmd.Flags().String("from-bbolt", "", "migrate gateways from the bbolt store at the given path")
+	storeMigrateCmd.Flags().StringSlice("from-etcd", nil, "migrate gateways from the etcd store with the given endpoints")
+
+	storeMigrateCmd.Flags().String("to-yaml", "", "migrate gateways into the yaml file store at the given path")
+	storeMigrateCmd.Flags().String("to-bbolt", "", "migrate gateways into the bbolt store at the given path")
+	storeMigrateCmd.Flags().StringSlice("to-etcd", nil, "migrate gateways into the etcd store with the given endpoints")
+
+	StoreCmd.AddCommand(storeMigrateCmd)
+}
+
+func runStoreMigrate(cmd *cobra.Command, args []string) error {
+	from, err := gatewayStoreFromFlags(cmd, "from")
+	if err != nil {
+		return fmt.Errorf("source store: %w", err)
+	}
+	defer from.Close()
+
+	to, err := gatewayStoreFromFlags(cmd, "to")
+	if err != nil {
+		return fmt.Errorf("destination store: %w", err)
+	}
+	defer to.Close()
+
+	var (
+		ctx      = context.Background()
+		gateways = from.Gateways()
+		migrated int
+	)
+
+	logrus.WithField("#gateways", len(gateways)).Info("migrating gateways")
+	for _, gw := range gateways {
+		if err := to.Add(ctx, gw); err != nil {
+			logrus.WithError(err).WithField("local-id", gw.LocalGatewayID).Error("unable to migrate gateway, skipping")
+			continue
+		}
+		migrated++
+	}
+
+	logrus.WithFields(logrus.Fields{"migrated": migrated, "total": len(gateways)}).Info("gateway store migration complete")
+	return nil
+}
+
+// gatewayStoreFromFlags builds the gateway.Store selected by the
+// `--<prefix>-yaml`, `--<prefix>-bbolt` or `--<prefix>-etcd` flags on cmd.
+func gatewayStoreFromFlags(cmd *cobra.Command, prefix string) (gateway.Store, error) {
+	yamlPath, _ := cmd.Flags().GetString(prefix + "-yaml")
+	bboltPath, _ := cmd.Flags().GetString(prefix + "-bbolt")
+	etcdEndpoints, _ := cmd.Flags().GetStringSlice(prefix + "-etcd")
+
+	switch {
+	case yamlPath != "":
+		return gateway.LoadGatewayYamlFileStore(yamlPath)
+	case bboltPath != "":
+		return gateway.LoadGatewayBboltStore(gateway.BboltStoreConfig{Path: bboltPath})
+	case len(etcdEndpoints) > 0:
+		return gateway.LoadGatewayEtcdStore(cmd.Context(), gateway.EtcdStoreConfig{Endpoints: etcdEndpoints})
+	default:
+		return nil, fmt.Errorf("no store specified, use --%s-yaml, --%s-bbolt or --%s-etcd", prefix, prefix, prefix)
+	}
+}
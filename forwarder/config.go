@@ -0,0 +1,121 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package forwarder
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Config is the forwarder configuration as loaded from the forwarder
+// configuration file.
+type Config struct {
+	BlockChain BlockChainConfig
+	Forwarder  ForwarderConfig
+}
+
+// BlockChainConfig configures the chain(s) the forwarder reads on-chain
+// gateway and router registrations from.
+type BlockChainConfig struct {
+	Polygon PolygonConfig
+}
+
+// PolygonConfig configures the Polygon RPC node the forwarder dials for
+// on-chain lookups.
+type PolygonConfig struct {
+	Endpoint      string
+	ChainID       uint64
+	Confirmations uint64
+}
+
+// ForwarderConfig configures the gateways and routers the forwarder serves.
+type ForwarderConfig struct {
+	Gateways      GatewaysConfig
+	Routers       RoutersConfig
+	RegistryCache RegistryCacheConfig
+}
+
+// RegistryCacheConfig configures the persistent cache of on-chain gateway
+// and router registry lookups that the forwarder falls back to when the
+// RPC node is unreachable. Caching is disabled when Path is empty.
+type RegistryCacheConfig struct {
+	Path string
+	// TTL bounds how long a cached entry is considered fresh. Entries older
+	// than TTL are still used as a fallback when the chain is unreachable,
+	// but are reported as stale. Defaults to 24h when nil.
+	TTL *time.Duration
+}
+
+// GatewaysConfig configures which gateways the forwarder accepts data from.
+type GatewaysConfig struct {
+	// RegistryAddress is the gateway registry contract that is consulted to
+	// check gateways in Store are onboarded. When nil all gateways in Store
+	// are trusted.
+	RegistryAddress *common.Address
+	// Store configures the gateway store backend.
+	Store GatewayStoreConfig
+}
+
+// GatewayStoreConfig selects and configures the gateway store backend. Only
+// one of YamlStorePath, Etcd or Bbolt should be set.
+type GatewayStoreConfig struct {
+	// YamlStorePath is the path to a yaml file that holds the gateways the
+	// forwarder serves. Requires a restart to pick up changes.
+	YamlStorePath *string
+	// Etcd configures an etcd v3 backed store, shared by multiple forwarder
+	// replicas.
+	Etcd *GatewayEtcdStoreConfig
+	// Bbolt configures an embedded, crash-safe store for single-node
+	// deployments.
+	Bbolt *GatewayBboltStoreConfig
+}
+
+// GatewayEtcdStoreConfig configures the etcd v3 gateway store backend.
+type GatewayEtcdStoreConfig struct {
+	Endpoints   []string
+	DialTimeout time.Duration
+	Username    string
+	Password    string
+}
+
+// GatewayBboltStoreConfig configures the embedded bbolt gateway store
+// backend.
+type GatewayBboltStoreConfig struct {
+	Path string
+}
+
+// RoutersConfig configures where the forwarder retrieves its routing table
+// from.
+type RoutersConfig struct {
+	OnChain     OnChainRoutersConfig
+	ThingsIXApi ThingsIXApiRoutersConfig
+}
+
+// OnChainRoutersConfig configures retrieval of the routing table directly
+// from the router registry contract.
+type OnChainRoutersConfig struct {
+	UpdateInterval   *time.Duration
+	RegistryContract common.Address
+}
+
+// ThingsIXApiRoutersConfig configures retrieval of the routing table from
+// the ThingsIX API, a cached snapshot of the on-chain router registry.
+type ThingsIXApiRoutersConfig struct {
+	UpdateInterval *time.Duration
+	Endpoint       *string
+}
@@ -80,6 +80,23 @@ func loadGatewayStore(cfg *Config) (gateway.Store, error) {
 		if store, err = gateway.LoadGatewayYamlFileStore(*cfg.Forwarder.Gateways.Store.YamlStorePath); err != nil {
 			logrus.WithError(err).Fatal("unable to load gateway store")
 		}
+	} else if cfg.Forwarder.Gateways.Store.Etcd != nil {
+		logrus.WithField("endpoints", cfg.Forwarder.Gateways.Store.Etcd.Endpoints).Info("use etcd gateway store")
+		if store, err = gateway.LoadGatewayEtcdStore(context.Background(), gateway.EtcdStoreConfig{
+			Endpoints:   cfg.Forwarder.Gateways.Store.Etcd.Endpoints,
+			DialTimeout: cfg.Forwarder.Gateways.Store.Etcd.DialTimeout,
+			Username:    cfg.Forwarder.Gateways.Store.Etcd.Username,
+			Password:    cfg.Forwarder.Gateways.Store.Etcd.Password,
+		}); err != nil {
+			logrus.WithError(err).Fatal("unable to load gateway store")
+		}
+	} else if cfg.Forwarder.Gateways.Store.Bbolt != nil {
+		logrus.WithField("path", cfg.Forwarder.Gateways.Store.Bbolt.Path).Info("use bbolt gateway store")
+		if store, err = gateway.LoadGatewayBboltStore(gateway.BboltStoreConfig{
+			Path: cfg.Forwarder.Gateways.Store.Bbolt.Path,
+		}); err != nil {
+			logrus.WithError(err).Fatal("unable to load gateway store")
+		}
 	} else {
 		// no gateway store configured, fallback to default yaml gateway store
 		// in $HOME/gateway-store.yaml
@@ -97,7 +114,12 @@ func loadGatewayStore(cfg *Config) (gateway.Store, error) {
 	return store, err
 }
 
-func acceptOnlyOnboardedAndRegistryGateways(cfg *Config, store gateway.Store) (map[lorawan.EUI64]*gateway.Gateway, map[lorawan.EUI64]*gateway.Gateway, error) {
+// acceptOnlyOnboardedAndRegistryGateways takes the registry cache the caller
+// opened once at startup rather than opening its own: bbolt's exclusive
+// file lock means a second Open from a concurrently running router refresh
+// would otherwise block for the full open timeout and silently run without
+// a cache.
+func acceptOnlyOnboardedAndRegistryGateways(cfg *Config, store gateway.Store, cache *RegistryCache) (map[lorawan.EUI64]*gateway.Gateway, map[lorawan.EUI64]*gateway.Gateway, error) {
 	client, err := ethclient.Dial(cfg.BlockChain.Polygon.Endpoint)
 	if err != nil {
 		logrus.WithError(err).Error("unable to dial blockchain RPC node")
@@ -121,6 +143,19 @@ func acceptOnlyOnboardedAndRegistryGateways(cfg *Config, store gateway.Store) (m
 		rgw, err := registry.Gateways(nil, gateway.ID())
 		if err != nil {
 			logrus.WithError(err).Error("unable to retrieve gateway details from registry")
+			if cache != nil {
+				if cached, stale, ok := cache.GetGateway(gateway.ID()); ok {
+					gateway.Owner = cached.Owner
+					trustedGatewaysByLocalID[gateway.LocalGatewayID] = gateway
+					trustedGatewaysByNetworkID[gateway.NetworkGatewayID] = gateway
+					logrus.WithFields(logrus.Fields{
+						"local-id":  gateway.LocalGatewayID,
+						"owner":     cached.Owner,
+						"stale":     stale,
+						"cached-at": cached.ObservedAt,
+					}).Warn("registry unreachable, trusting gateway from cache")
+				}
+			}
 			continue
 		}
 
@@ -137,6 +172,12 @@ func acceptOnlyOnboardedAndRegistryGateways(cfg *Config, store gateway.Store) (m
 				"owner":        gateway.Owner,
 				"freq-plan":    frequency_plan.FromBlockchain(frequency_plan.BlockchainFrequencyPlan(rgw.FrequencyPlan)),
 			}).Debug("loaded gateway from store")
+
+			if cache != nil {
+				if err := cache.PutGateway(gateway.ID(), cachedGatewayRegistration{Owner: gateway.Owner, ObservedAt: time.Now()}); err != nil {
+					logrus.WithError(err).Warn("unable to persist gateway registration to cache")
+				}
+			}
 		} else {
 			l := logrus.WithFields(logrus.Fields{
 				"id":         fmt.Sprintf("%x", gateway.ID()),
@@ -154,7 +195,9 @@ func acceptOnlyOnboardedAndRegistryGateways(cfg *Config, store gateway.Store) (m
 	return trustedGatewaysByLocalID, trustedGatewaysByNetworkID, err
 }
 
-func onboardedAndRegisteredGateways(cfg *Config, store gateway.Store) (map[lorawan.EUI64]*gateway.Gateway, map[lorawan.EUI64]*gateway.Gateway, error) {
+// onboardedAndRegisteredGateways takes the shared registry cache opened by
+// the caller at startup; see acceptOnlyOnboardedAndRegistryGateways.
+func onboardedAndRegisteredGateways(cfg *Config, store gateway.Store, cache *RegistryCache) (map[lorawan.EUI64]*gateway.Gateway, map[lorawan.EUI64]*gateway.Gateway, error) {
 	// If gateway registry is not configured accept data from all gateways from the store.
 	// This is temporary until gateway onboards are made possible and ThingsIX moves from
 	// data-only to a network with rewards.
@@ -175,10 +218,13 @@ func onboardedAndRegisteredGateways(cfg *Config, store gateway.Store) (map[loraw
 		return trustedGatewaysByLocalID, trustedGatewaysByNetworkID, nil
 	}
 
-	return acceptOnlyOnboardedAndRegistryGateways(cfg, store)
+	return acceptOnlyOnboardedAndRegistryGateways(cfg, store, cache)
 }
 
-func fetchRoutersFromChain(cfg *Config, accounter Accounter) (RoutesUpdaterFunc, time.Duration, error) {
+// fetchRoutersFromChain takes the registry cache the caller opened once at
+// startup rather than opening its own; see
+// acceptOnlyOnboardedAndRegistryGateways.
+func fetchRoutersFromChain(cfg *Config, accounter Accounter, cache *RegistryCache) (RoutesUpdaterFunc, time.Duration, error) {
 	interval := 30 * time.Minute // default refresh interval
 	if cfg.Forwarder.Routers.OnChain.UpdateInterval != nil {
 		if *cfg.Forwarder.Routers.OnChain.UpdateInterval < time.Minute {
@@ -191,65 +237,124 @@ func fetchRoutersFromChain(cfg *Config, accounter Accounter) (RoutesUpdaterFunc,
 	logrus.WithField("interval", interval).Info("retrieve routes on chain")
 
 	return func() ([]*Router, error) {
-		client, err := dialRPCNode(cfg)
+		cachedRouters, _, err := refreshRouterSnapshot(cfg, cache)
 		if err != nil {
 			return nil, err
 		}
-		defer client.Close()
 
-		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-		defer cancel()
+		routers := make([]*Router, len(cachedRouters))
+		for i, r := range cachedRouters {
+			routers[i] = NewRouter(r.ID, r.Endpoint, false, r.Networks, r.Owner, accounter)
+		}
+		return routers, nil
+	}, interval, nil
+}
 
-		// determine latest confirmed block
-		head, err := client.HeaderByNumber(ctx, nil)
-		if err != nil {
-			return nil, fmt.Errorf("unable to determine chain head: %w", err)
+// refreshRouterSnapshot fetches the current routing table from the router
+// registry contract, falling back to cache on failure and write-through
+// caching on success. It backs both the periodic router refresh and the
+// admin API's ReloadRouters so the two never disagree on caching
+// policy. cache may be nil, in which case a chain failure is returned as
+// an error instead of falling back.
+func refreshRouterSnapshot(cfg *Config, cache *RegistryCache) ([]cachedRouter, uint64, error) {
+	cachedRouters, confirmedBlock, err := routerRegistrySnapshot(cfg)
+	if err != nil {
+		logrus.WithError(err).Error("unable to retrieve routers from chain")
+		if cache != nil {
+			if snapshot, stale, ok := cache.GetRouterSnapshot(); ok {
+				logrus.WithFields(logrus.Fields{
+					"confirmed-block": snapshot.ConfirmedBlock,
+					"stale":           stale,
+					"cached-at":       snapshot.ObservedAt,
+				}).Warn("chain unreachable, using cached router snapshot")
+				return snapshot.Routers, snapshot.ConfirmedBlock, nil
+			}
 		}
+		return nil, 0, err
+	}
 
-		if head.Number.Uint64() < cfg.BlockChain.Polygon.Confirmations {
-			return nil, nil // no confirmed blocks yet
+	// A fetch that yielded no confirmed block (chain head not yet past
+	// Confirmations, so routerRegistrySnapshot read nothing) or no routers
+	// is not written through: it would overwrite a good snapshot and turn
+	// the next RPC outage into an empty routing table, precisely what the
+	// cache exists to prevent. A registry that really holds no routers is
+	// therefore never cached.
+	if cache != nil && confirmedBlock > 0 && len(cachedRouters) > 0 {
+		if err := cache.PutRouterSnapshot(cachedRouterSnapshot{
+			ConfirmedBlock: confirmedBlock,
+			Routers:        cachedRouters,
+			ObservedAt:     time.Now(),
+		}); err != nil {
+			logrus.WithError(err).Warn("unable to persist router snapshot to cache")
 		}
+	}
 
-		var (
-			confirmedBlock = head.Number.Uint64() - cfg.BlockChain.Polygon.Confirmations
-			callOpts       = &bind.CallOpts{
-				BlockNumber: new(big.Int).SetUint64(confirmedBlock),
-			}
-		)
+	return cachedRouters, confirmedBlock, nil
+}
 
-		registry, err := router_registry.NewRouterRegistryCaller(cfg.Forwarder.Routers.OnChain.RegistryContract, client)
-		if err != nil {
-			return nil, fmt.Errorf("unable to instantiate router registry bindings")
+// routerRegistrySnapshot reads the current routing table straight from the
+// router registry contract, without constructing *Router values. Callers
+// that don't need a usable Accounter-bound routing table, such as the admin
+// API, can use this directly instead of fetchRoutersFromChain.
+func routerRegistrySnapshot(cfg *Config) ([]cachedRouter, uint64, error) {
+	client, err := dialRPCNode(cfg)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// determine latest confirmed block
+	head, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to determine chain head: %w", err)
+	}
+
+	if head.Number.Uint64() < cfg.BlockChain.Polygon.Confirmations {
+		return nil, 0, nil // no confirmed blocks yet
+	}
+
+	var (
+		confirmedBlock = head.Number.Uint64() - cfg.BlockChain.Polygon.Confirmations
+		callOpts       = &bind.CallOpts{
+			BlockNumber: new(big.Int).SetUint64(confirmedBlock),
 		}
+	)
+
+	registry, err := router_registry.NewRouterRegistryCaller(cfg.Forwarder.Routers.OnChain.RegistryContract, client)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to instantiate router registry bindings")
+	}
+
+	routerCount, err := registry.RouterCount(callOpts)
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to determine router count: %w", err)
+	}
 
-		routerCount, err := registry.RouterCount(callOpts)
+	var (
+		cachedRouters []cachedRouter
+		pageSize      = int64(50)
+	)
+	for i := int64(0); i < routerCount.Int64(); i += pageSize {
+		fetchedRouters, err := registry.RoutersPaged(callOpts, big.NewInt(i), big.NewInt(i+pageSize))
 		if err != nil {
-			return nil, fmt.Errorf("unable to determine router count: %w", err)
+			return nil, 0, fmt.Errorf("unable to retrieve routers from registry: %w", err)
 		}
 
-		var (
-			routers  []*Router
-			pageSize = int64(50)
-		)
-		for i := int64(0); i*pageSize < routerCount.Int64(); i += pageSize {
-			fetchedRouters, err := registry.RoutersPaged(callOpts, big.NewInt(i), big.NewInt(i+pageSize))
-			if err != nil {
-				return nil, fmt.Errorf("unable to retrieve routers from registry: %w", err)
-			}
-
-			for _, r := range fetchedRouters {
-				netids := make([]lorawan.NetID, len(r.Networks))
-				for i, id := range r.Networks {
-					var netid [4]byte
-					binary.BigEndian.PutUint32(netid[:], uint32(id.Uint64()))
-					netids[i] = lorawan.NetID{netid[1], netid[2], netid[3]}
-				}
-				routers = append(routers, NewRouter(r.Id, r.Endpoint, false, netids, r.Owner, accounter))
+		for _, r := range fetchedRouters {
+			netids := make([]lorawan.NetID, len(r.Networks))
+			for i, id := range r.Networks {
+				var netid [4]byte
+				binary.BigEndian.PutUint32(netid[:], uint32(id.Uint64()))
+				netids[i] = lorawan.NetID{netid[1], netid[2], netid[3]}
 			}
+			cachedRouters = append(cachedRouters, cachedRouter{ID: r.Id, Endpoint: r.Endpoint, Networks: netids, Owner: r.Owner})
 		}
+	}
 
-		return routers, nil
-	}, interval, nil
+	return cachedRouters, confirmedBlock, nil
 }
 
 func dialRPCNode(cfg *Config) (*ethclient.Client, error) {
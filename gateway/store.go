@@ -0,0 +1,67 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"context"
+	"errors"
+
+	"github.com/brocaar/lorawan"
+)
+
+// ErrWatchNotSupported is returned by Store implementations that have no way
+// to observe gateways added or removed by other processes.
+var ErrWatchNotSupported = errors.New("gateway store: watch not supported")
+
+// StoreEventType indicates why a StoreEvent was emitted.
+type StoreEventType int
+
+const (
+	// StoreEventAdd indicates a gateway was added to the store.
+	StoreEventAdd StoreEventType = iota
+	// StoreEventDelete indicates a gateway was removed from the store.
+	StoreEventDelete
+)
+
+// StoreEvent is emitted by a Store that supports Watch whenever a gateway is
+// added to or removed from the store, either by this process or by another
+// one sharing the same backend.
+type StoreEvent struct {
+	Type    StoreEventType
+	Gateway *Gateway
+}
+
+// Store is implemented by the different backends that keep track of the
+// gateways the forwarder accepts data from. Implementations range from a
+// static file on disk to backends shared by multiple forwarder replicas.
+type Store interface {
+	// Gateways returns all gateways currently in the store.
+	Gateways() []*Gateway
+	// Add persists gw in the store. It returns an error if a gateway with
+	// the same LocalGatewayID already exists.
+	Add(ctx context.Context, gw *Gateway) error
+	// Delete removes the gateway with the given local gateway id from the
+	// store. It is not an error to delete a gateway that doesn't exist.
+	Delete(ctx context.Context, localID lorawan.EUI64) error
+	// Watch streams a StoreEvent for every gateway that is added to or
+	// removed from the store after Watch was called. Implementations that
+	// can't observe changes made by other processes return
+	// ErrWatchNotSupported.
+	Watch(ctx context.Context) (<-chan StoreEvent, error)
+	// Close releases the resources held by the store.
+	Close() error
+}
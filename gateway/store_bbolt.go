@@ -0,0 +1,114 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	bolt "go.etcd.io/bbolt"
+)
+
+// gatewaysBucket is the single bbolt bucket all gateway records are kept in.
+var gatewaysBucket = []byte("gateways")
+
+// BboltStoreConfig configures a bbolt backed gateway Store.
+type BboltStoreConfig struct {
+	// Path is the file the bbolt database is stored in.
+	Path string
+}
+
+// bboltStore is a Store backed by an embedded bbolt database. It gives
+// single-node deployments a crash-safe alternative to the yaml store, which
+// risks losing concurrent changes since it is rewritten in full on every
+// update.
+type bboltStore struct {
+	db *bolt.DB
+}
+
+// LoadGatewayBboltStore opens (creating if necessary) the bbolt database at
+// cfg.Path and returns a Store backed by it.
+func LoadGatewayBboltStore(cfg BboltStoreConfig) (Store, error) {
+	db, err := bolt.Open(cfg.Path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("unable to open bbolt store %s: %w", cfg.Path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(gatewaysBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to initialize bbolt store %s: %w", cfg.Path, err)
+	}
+
+	return &bboltStore{db: db}, nil
+}
+
+func (s *bboltStore) Gateways() []*Gateway {
+	var gateways []*Gateway
+
+	_ = s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(gatewaysBucket).ForEach(func(k, v []byte) error {
+			var localID lorawan.EUI64
+			copy(localID[:], k)
+
+			gw, err := decodeGatewayRecord(localID, v)
+			if err != nil {
+				return nil // skip corrupt entry, don't fail the whole listing
+			}
+			gateways = append(gateways, gw)
+			return nil
+		})
+	})
+
+	return gateways
+}
+
+func (s *bboltStore) Add(ctx context.Context, gw *Gateway) error {
+	raw, err := encodeGatewayRecord(gw)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(gatewaysBucket)
+		if bucket.Get(gw.LocalGatewayID[:]) != nil {
+			return fmt.Errorf("gateway %s already exists", gw.LocalGatewayID)
+		}
+		return bucket.Put(gw.LocalGatewayID[:], raw)
+	})
+}
+
+func (s *bboltStore) Delete(ctx context.Context, localID lorawan.EUI64) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(gatewaysBucket).Delete(localID[:])
+	})
+}
+
+// Watch is not supported: bbolt is an embedded, single-process database so
+// there is no other writer whose changes a replica would need to observe.
+func (s *bboltStore) Watch(ctx context.Context) (<-chan StoreEvent, error) {
+	return nil, ErrWatchNotSupported
+}
+
+func (s *bboltStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/brocaar/lorawan"
+)
+
+func TestBboltStoreAddRejectsDuplicate(t *testing.T) {
+	store, err := LoadGatewayBboltStore(BboltStoreConfig{Path: filepath.Join(t.TempDir(), "gateways.db")})
+	if err != nil {
+		t.Fatalf("LoadGatewayBboltStore: %v", err)
+	}
+	defer store.Close()
+
+	localID := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+	gw, err := GenerateNewGateway(localID)
+	if err != nil {
+		t.Fatalf("GenerateNewGateway: %v", err)
+	}
+
+	if err := store.Add(context.Background(), gw); err != nil {
+		t.Fatalf("first Add: %v", err)
+	}
+
+	other, err := GenerateNewGateway(localID)
+	if err != nil {
+		t.Fatalf("GenerateNewGateway: %v", err)
+	}
+	if err := store.Add(context.Background(), other); err == nil {
+		t.Fatal("Add of an existing LocalGatewayID succeeded, want an already-exists error")
+	}
+
+	gateways := store.Gateways()
+	if len(gateways) != 1 {
+		t.Fatalf("len(Gateways()) = %d, want 1", len(gateways))
+	}
+	if gateways[0].PrivateKey.D.Cmp(gw.PrivateKey.D) != 0 {
+		t.Error("duplicate Add overwrote the original gateway record")
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brocaar/lorawan"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// etcdGatewayPrefix namespaces all gateway keys this store writes so it can
+// safely share an etcd cluster with other ThingsIX components.
+const etcdGatewayPrefix = "thingsix/gateways/"
+
+// EtcdStoreConfig configures an etcd backed gateway Store.
+type EtcdStoreConfig struct {
+	// Endpoints are the etcd v3 client endpoints to dial.
+	Endpoints []string
+	// DialTimeout bounds how long the initial connection attempt may take.
+	// Defaults to 5s when zero.
+	DialTimeout time.Duration
+	// Username and Password are optional etcd auth credentials.
+	Username string
+	Password string
+}
+
+// etcdStore is a Store backed by etcd v3. Because all forwarder replicas
+// that share the same etcd cluster see the same keys, Watch lets them pick
+// up gateways added or removed by one another without a restart.
+type etcdStore struct {
+	client *clientv3.Client
+}
+
+// LoadGatewayEtcdStore dials the etcd cluster described by cfg and returns a
+// Store backed by it.
+func LoadGatewayEtcdStore(ctx context.Context, cfg EtcdStoreConfig) (Store, error) {
+	dialTimeout := cfg.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = 5 * time.Second
+	}
+
+	client, err := clientv3.New(clientv3.Config{
+		Endpoints:   cfg.Endpoints,
+		DialTimeout: dialTimeout,
+		Username:    cfg.Username,
+		Password:    cfg.Password,
+		Context:     ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial etcd: %w", err)
+	}
+
+	return &etcdStore{client: client}, nil
+}
+
+func etcdGatewayKey(localID lorawan.EUI64) string {
+	return etcdGatewayPrefix + localID.String()
+}
+
+func (s *etcdStore) Gateways() []*Gateway {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := s.client.Get(ctx, etcdGatewayPrefix, clientv3.WithPrefix())
+	if err != nil {
+		return nil
+	}
+
+	gateways := make([]*Gateway, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		gw, err := decodeGatewayRecord(eui64FromEtcdKey(string(kv.Key)), kv.Value)
+		if err != nil {
+			continue
+		}
+		gateways = append(gateways, gw)
+	}
+	return gateways
+}
+
+func eui64FromEtcdKey(key string) lorawan.EUI64 {
+	var id lorawan.EUI64
+	_ = id.UnmarshalText([]byte(strings.TrimPrefix(key, etcdGatewayPrefix)))
+	return id
+}
+
+func (s *etcdStore) Add(ctx context.Context, gw *Gateway) error {
+	raw, err := encodeGatewayRecord(gw)
+	if err != nil {
+		return err
+	}
+
+	key := etcdGatewayKey(gw.LocalGatewayID)
+	// Only create the key if it doesn't exist yet: a non-zero create
+	// revision means some earlier Add already claimed it.
+	txn := s.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(key), "=", 0)).
+		Then(clientv3.OpPut(key, string(raw)))
+
+	resp, err := txn.Commit()
+	if err != nil {
+		return fmt.Errorf("unable to store gateway %s in etcd: %w", gw.LocalGatewayID, err)
+	}
+	if !resp.Succeeded {
+		return fmt.Errorf("gateway %s already exists", gw.LocalGatewayID)
+	}
+	return nil
+}
+
+func (s *etcdStore) Delete(ctx context.Context, localID lorawan.EUI64) error {
+	if _, err := s.client.Delete(ctx, etcdGatewayKey(localID)); err != nil {
+		return fmt.Errorf("unable to delete gateway %s from etcd: %w", localID, err)
+	}
+	return nil
+}
+
+func (s *etcdStore) Watch(ctx context.Context) (<-chan StoreEvent, error) {
+	events := make(chan StoreEvent)
+	watchChan := s.client.Watch(ctx, etcdGatewayPrefix, clientv3.WithPrefix())
+
+	go func() {
+		defer close(events)
+		for resp := range watchChan {
+			for _, ev := range resp.Events {
+				var (
+					localID = eui64FromEtcdKey(string(ev.Kv.Key))
+					evt     StoreEvent
+				)
+				if ev.Type == clientv3.EventTypeDelete {
+					evt = StoreEvent{Type: StoreEventDelete, Gateway: &Gateway{LocalGatewayID: localID}}
+				} else {
+					gw, err := decodeGatewayRecord(localID, ev.Kv.Value)
+					if err != nil {
+						continue
+					}
+					evt = StoreEvent{Type: StoreEventAdd, Gateway: gw}
+				}
+
+				select {
+				case events <- evt:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (s *etcdStore) Close() error {
+	return s.client.Close()
+}
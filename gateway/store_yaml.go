@@ -0,0 +1,227 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/gob"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/brocaar/lorawan"
+	"github.com/ethereum/go-ethereum/common"
+	"gopkg.in/yaml.v3"
+)
+
+// yamlGatewayRecord is the on-disk representation of a single gateway entry
+// in the yaml gateway store.
+type yamlGatewayRecord struct {
+	LocalGatewayID lorawan.EUI64 `yaml:"local_id"`
+	Owner          string        `yaml:"owner"`
+	PrivateKey     string        `yaml:"private_key"`
+}
+
+// yamlFileStore is a Store backed by a single yaml file on disk. It is the
+// simplest store and requires no additional infrastructure, but since it is
+// rewritten in full on every change it doesn't support sharing between
+// multiple forwarder replicas and a change made by an operator requires a
+// forwarder restart to take effect.
+type yamlFileStore struct {
+	mu       sync.Mutex
+	path     string
+	gateways map[lorawan.EUI64]*Gateway
+}
+
+// LoadGatewayYamlFileStore loads the gateways stored in the yaml file at
+// path. When the file doesn't exist an empty store backed by path is
+// returned so it can be populated through Add.
+func LoadGatewayYamlFileStore(path string) (Store, error) {
+	store := &yamlFileStore{
+		path:     path,
+		gateways: make(map[lorawan.EUI64]*Gateway),
+	}
+
+	raw, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to read gateway store %s: %w", path, err)
+	}
+
+	var records []yamlGatewayRecord
+	if err := yaml.Unmarshal(raw, &records); err != nil {
+		return nil, fmt.Errorf("unable to parse gateway store %s: %w", path, err)
+	}
+
+	for _, rec := range records {
+		gw, err := gatewayFromRecord(rec.LocalGatewayID, common.HexToAddress(rec.Owner), rec.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load gateway %s from %s: %w", rec.LocalGatewayID, path, err)
+		}
+		store.gateways[gw.LocalGatewayID] = gw
+	}
+
+	return store, nil
+}
+
+func (s *yamlFileStore) Gateways() []*Gateway {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	gateways := make([]*Gateway, 0, len(s.gateways))
+	for _, gw := range s.gateways {
+		gateways = append(gateways, gw)
+	}
+	return gateways
+}
+
+func (s *yamlFileStore) Add(ctx context.Context, gw *Gateway) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.gateways[gw.LocalGatewayID]; exists {
+		return fmt.Errorf("gateway %s already exists", gw.LocalGatewayID)
+	}
+
+	s.gateways[gw.LocalGatewayID] = gw
+	return s.persistLocked()
+}
+
+func (s *yamlFileStore) Delete(ctx context.Context, localID lorawan.EUI64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.gateways, localID)
+	return s.persistLocked()
+}
+
+func (s *yamlFileStore) Watch(ctx context.Context) (<-chan StoreEvent, error) {
+	return nil, ErrWatchNotSupported
+}
+
+func (s *yamlFileStore) Close() error {
+	return nil
+}
+
+// persistLocked rewrites the yaml file with the current contents of the
+// store. Callers must hold s.mu.
+func (s *yamlFileStore) persistLocked() error {
+	records := make([]yamlGatewayRecord, 0, len(s.gateways))
+	for _, gw := range s.gateways {
+		pemBytes, err := privateKeyToPEM(gw.PrivateKey)
+		if err != nil {
+			return fmt.Errorf("unable to encode private key of gateway %s: %w", gw.LocalGatewayID, err)
+		}
+		records = append(records, yamlGatewayRecord{
+			LocalGatewayID: gw.LocalGatewayID,
+			Owner:          gw.Owner.Hex(),
+			PrivateKey:     string(pemBytes),
+		})
+	}
+
+	raw, err := yaml.Marshal(records)
+	if err != nil {
+		return fmt.Errorf("unable to marshal gateway store: %w", err)
+	}
+
+	if err := os.WriteFile(s.path, raw, 0600); err != nil {
+		return fmt.Errorf("unable to write gateway store %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+// privateKeyToPEM encodes priv as a PKCS#8 PEM block, the compact encoding
+// shared by all gateway store backends.
+func privateKeyToPEM(priv *ecdsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// gatewayFromRecord reconstructs a Gateway from its local id, owner and PEM
+// encoded private key, as used by the yaml, etcd and bbolt store backends.
+func gatewayFromRecord(localID lorawan.EUI64, owner common.Address, privPEM string) (*Gateway, error) {
+	block, _ := pem.Decode([]byte(privPEM))
+	if block == nil {
+		return nil, fmt.Errorf("invalid PEM encoded private key")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse private key: %w", err)
+	}
+
+	priv, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key is not an ECDSA key")
+	}
+
+	gw, err := NewGateway(localID, priv)
+	if err != nil {
+		return nil, err
+	}
+	gw.Owner = owner
+	return gw, nil
+}
+
+// gatewayRecord is the gob encoded envelope the etcd and bbolt stores keep a
+// gateway under. Unlike the yaml store, which spreads owner and private key
+// over separate yaml fields, both backends store a single opaque value per
+// key, so they share this envelope rather than inventing their own.
+type gatewayRecord struct {
+	Owner      common.Address
+	PrivateKey string
+}
+
+// encodeGatewayRecord gob-encodes gw as a gatewayRecord, as stored by the
+// etcd and bbolt backends.
+func encodeGatewayRecord(gw *Gateway) ([]byte, error) {
+	pemBytes, err := privateKeyToPEM(gw.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode private key of gateway %s: %w", gw.LocalGatewayID, err)
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(gatewayRecord{Owner: gw.Owner, PrivateKey: string(pemBytes)}); err != nil {
+		return nil, fmt.Errorf("unable to encode gateway record of %s: %w", gw.LocalGatewayID, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeGatewayRecord reverses encodeGatewayRecord. It also accepts the bare
+// PEM encoded private key the etcd and bbolt backends stored before
+// gatewayRecord existed, so upgrading doesn't strand gateways added by an
+// older build without an owner.
+func decodeGatewayRecord(localID lorawan.EUI64, raw []byte) (*Gateway, error) {
+	if bytes.HasPrefix(raw, []byte("-----BEGIN")) {
+		return gatewayFromRecord(localID, common.Address{}, string(raw))
+	}
+
+	var rec gatewayRecord
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&rec); err != nil {
+		return nil, fmt.Errorf("unable to decode gateway record of %s: %w", localID, err)
+	}
+	return gatewayFromRecord(localID, rec.Owner, rec.PrivateKey)
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Stichting ThingsIX Foundation
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package gateway
+
+import (
+	"testing"
+
+	"github.com/brocaar/lorawan"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestEncodeDecodeGatewayRecordRoundTrip(t *testing.T) {
+	localID := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+	owner := common.HexToAddress("0x000000000000000000000000000000000000a1")
+
+	gw, err := GenerateNewGateway(localID)
+	if err != nil {
+		t.Fatalf("GenerateNewGateway: %v", err)
+	}
+	gw.Owner = owner
+
+	raw, err := encodeGatewayRecord(gw)
+	if err != nil {
+		t.Fatalf("encodeGatewayRecord: %v", err)
+	}
+
+	got, err := decodeGatewayRecord(localID, raw)
+	if err != nil {
+		t.Fatalf("decodeGatewayRecord: %v", err)
+	}
+
+	if got.LocalGatewayID != localID {
+		t.Errorf("LocalGatewayID = %s, want %s", got.LocalGatewayID, localID)
+	}
+	if got.Owner != owner {
+		t.Errorf("Owner = %s, want %s", got.Owner, owner)
+	}
+	if got.PrivateKey.D.Cmp(gw.PrivateKey.D) != 0 {
+		t.Errorf("decoded private key does not match encoded private key")
+	}
+}
+
+func TestDecodeGatewayRecordLegacyPEM(t *testing.T) {
+	localID := lorawan.EUI64{1, 2, 3, 4, 5, 6, 7, 8}
+
+	gw, err := GenerateNewGateway(localID)
+	if err != nil {
+		t.Fatalf("GenerateNewGateway: %v", err)
+	}
+
+	legacy, err := privateKeyToPEM(gw.PrivateKey)
+	if err != nil {
+		t.Fatalf("privateKeyToPEM: %v", err)
+	}
+
+	// Records written before gatewayRecord existed are a bare PEM encoded
+	// private key with no owner, stored verbatim as the value.
+	got, err := decodeGatewayRecord(localID, legacy)
+	if err != nil {
+		t.Fatalf("decodeGatewayRecord: %v", err)
+	}
+
+	if got.Owner != (common.Address{}) {
+		t.Errorf("Owner = %s, want zero address for a legacy record", got.Owner)
+	}
+	if got.PrivateKey.D.Cmp(gw.PrivateKey.D) != 0 {
+		t.Errorf("decoded private key does not match encoded private key")
+	}
+}